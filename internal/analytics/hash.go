@@ -0,0 +1,14 @@
+package analytics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// EnvelopeHash returns a hex-encoded sha256 of envelopeXdr, used to tag a
+// StorageGrowthReport with a stable identifier for the transaction it came
+// from.
+func EnvelopeHash(envelopeXdr string) string {
+	sum := sha256.Sum256([]byte(envelopeXdr))
+	return hex.EncodeToString(sum[:])
+}
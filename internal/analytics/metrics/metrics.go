@@ -0,0 +1,65 @@
+// Package metrics registers the Prometheus collectors hintents exposes when
+// run as a long-lived analysis service, and feeds them from the same
+// analytics.StorageGrowthReport that drives the human-readable report.
+package metrics
+
+import (
+	"time"
+
+	"github.com/dotandev/hintents/internal/analytics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds the collectors hintents registers for a monitoring
+// pipeline to scrape.
+type Metrics struct {
+	StorageDelta       prometheus.Histogram
+	Fee                prometheus.Histogram
+	SimulationDuration prometheus.Histogram
+	SimulationsTotal   *prometheus.CounterVec
+	ContractStorage    *prometheus.GaugeVec
+}
+
+// New registers hintents' collectors against reg and returns the handle
+// used to observe them.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		StorageDelta: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "hintents_storage_delta_bytes",
+			Help:    "Per-simulation change in contract storage footprint, in bytes.",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 10),
+		}),
+		Fee: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "hintents_fee_stroops",
+			Help:    "Fee charged for a simulated transaction, in stroops.",
+			Buckets: prometheus.ExponentialBuckets(100, 4, 10),
+		}),
+		SimulationDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "hintents_simulation_duration_seconds",
+			Help:    "Time taken to simulate a single transaction.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		SimulationsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hintents_simulations_total",
+			Help: "Count of simulations run, partitioned by outcome status.",
+		}, []string{"status"}),
+		ContractStorage: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hintents_contract_storage_bytes",
+			Help: "Most recently observed storage footprint for a contract.",
+		}, []string{"contract_id"}),
+	}
+
+	reg.MustRegister(m.StorageDelta, m.Fee, m.SimulationDuration, m.SimulationsTotal, m.ContractStorage)
+
+	return m
+}
+
+// ObserveReport records a completed simulation's storage growth report, fee
+// and wall-clock duration against the registered collectors.
+func (m *Metrics) ObserveReport(report *analytics.StorageGrowthReport, fee int64, duration time.Duration, status string) {
+	m.StorageDelta.Observe(float64(report.DeltaBytes))
+	m.Fee.Observe(float64(fee))
+	m.SimulationDuration.Observe(duration.Seconds())
+	m.SimulationsTotal.WithLabelValues(status).Inc()
+	m.ContractStorage.WithLabelValues(report.ContractID).Set(float64(report.AfterBytes))
+}
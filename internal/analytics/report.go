@@ -0,0 +1,60 @@
+package analytics
+
+// KeyDelta is the before/after footprint of a single contract storage key.
+type KeyDelta struct {
+	Key    string
+	Before int64
+	After  int64
+	Delta  int64
+}
+
+// StorageGrowthReport summarizes how a simulated transaction changed a
+// contract's on-chain storage footprint.
+type StorageGrowthReport struct {
+	ContractID   string
+	EnvelopeHash string
+	LedgerSeq    uint32
+
+	BeforeBytes int64
+	AfterBytes  int64
+	DeltaBytes  int64
+
+	// PerKeyDelta is kept for PrintStorageReport's existing per-key diff
+	// output; PerKey carries the same information with before/after
+	// bytes for reporters that need it (see Reporter).
+	PerKeyDelta map[string]int64
+	PerKey      []KeyDelta
+}
+
+// BuildReport assembles a StorageGrowthReport from a contract's before/after
+// storage key sizes in bytes.
+func BuildReport(contractID, envelopeHash string, ledgerSeq uint32, before, after map[string]int64) *StorageGrowthReport {
+	report := &StorageGrowthReport{
+		ContractID:   contractID,
+		EnvelopeHash: envelopeHash,
+		LedgerSeq:    ledgerSeq,
+		PerKeyDelta:  make(map[string]int64),
+	}
+
+	keys := make(map[string]struct{}, len(before)+len(after))
+	for k := range before {
+		keys[k] = struct{}{}
+	}
+	for k := range after {
+		keys[k] = struct{}{}
+	}
+
+	for key := range keys {
+		b := before[key]
+		a := after[key]
+		delta := a - b
+
+		report.BeforeBytes += b
+		report.AfterBytes += a
+		report.DeltaBytes += delta
+		report.PerKeyDelta[key] = delta
+		report.PerKey = append(report.PerKey, KeyDelta{Key: key, Before: b, After: a, Delta: delta})
+	}
+
+	return report
+}
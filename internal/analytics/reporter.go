@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"io"
+	"sort"
+)
+
+// Reporter renders a StorageGrowthReport, together with the fee the
+// simulated transaction would charge, to w in some output format.
+type Reporter interface {
+	Report(w io.Writer, report *StorageGrowthReport, fee int64) error
+}
+
+// ReporterFor selects the Reporter for one of the --output flag's
+// supported formats: "text", "json", "ndjson" or "csv".
+func ReporterFor(format string) (Reporter, error) {
+	switch format {
+	case "", "text":
+		return TextReporter{}, nil
+	case "json":
+		return JSONReporter{}, nil
+	case "ndjson":
+		return NDJSONReporter{}, nil
+	case "csv":
+		return CSVReporter{}, nil
+	default:
+		return nil, unknownFormatError(format)
+	}
+}
+
+type unknownFormatError string
+
+func (e unknownFormatError) Error() string {
+	return "analytics: unknown output format " + string(e)
+}
+
+// reportDoc is the structured representation shared by JSONReporter and
+// NDJSONReporter. Its field names match the schema downstream jq
+// pipelines, spreadsheets and notebooks are expected to consume.
+type reportDoc struct {
+	EnvelopeHash string      `json:"envelope_hash"`
+	LedgerSeq    uint32      `json:"ledger_seq"`
+	BeforeBytes  int64       `json:"before_bytes"`
+	AfterBytes   int64       `json:"after_bytes"`
+	DeltaBytes   int64       `json:"delta_bytes"`
+	FeeStroops   int64       `json:"fee_stroops"`
+	PerKey       []perKeyDoc `json:"per_key"`
+}
+
+type perKeyDoc struct {
+	Key    string `json:"key"`
+	Before int64  `json:"before"`
+	After  int64  `json:"after"`
+	Delta  int64  `json:"delta"`
+}
+
+func toDoc(report *StorageGrowthReport, fee int64) reportDoc {
+	doc := reportDoc{
+		EnvelopeHash: report.EnvelopeHash,
+		LedgerSeq:    report.LedgerSeq,
+		BeforeBytes:  report.BeforeBytes,
+		AfterBytes:   report.AfterBytes,
+		DeltaBytes:   report.DeltaBytes,
+		FeeStroops:   fee,
+	}
+	for _, kd := range report.PerKey {
+		doc.PerKey = append(doc.PerKey, perKeyDoc{Key: kd.Key, Before: kd.Before, After: kd.After, Delta: kd.Delta})
+	}
+	// report.PerKey is built by ranging over a map (see BuildReport), so its
+	// order is nondeterministic run-to-run; sort by key for diffable,
+	// reproducible output.
+	sort.Slice(doc.PerKey, func(i, j int) bool { return doc.PerKey[i].Key < doc.PerKey[j].Key })
+	return doc
+}
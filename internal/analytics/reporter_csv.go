@@ -0,0 +1,49 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// CSVReporter renders one row per storage key, denormalized with the
+// report's envelope hash, ledger sequence and fee so the output can be
+// concatenated across batch runs and loaded straight into a spreadsheet.
+type CSVReporter struct{}
+
+func (CSVReporter) Report(w io.Writer, report *StorageGrowthReport, fee int64) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	header := []string{"envelope_hash", "ledger_seq", "key", "before_bytes", "after_bytes", "delta_bytes", "fee_stroops"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	ledgerSeq := fmt.Sprintf("%d", report.LedgerSeq)
+	feeStr := fmt.Sprintf("%d", fee)
+
+	// report.PerKey is built by ranging over a map (see BuildReport), so its
+	// order is nondeterministic run-to-run; sort a copy by key so output is
+	// diffable and reproducible without mutating the caller's report.
+	perKey := append([]KeyDelta(nil), report.PerKey...)
+	sort.Slice(perKey, func(i, j int) bool { return perKey[i].Key < perKey[j].Key })
+
+	for _, kd := range perKey {
+		row := []string{
+			report.EnvelopeHash,
+			ledgerSeq,
+			kd.Key,
+			fmt.Sprintf("%d", kd.Before),
+			fmt.Sprintf("%d", kd.After),
+			fmt.Sprintf("%d", kd.Delta),
+			feeStr,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return cw.Error()
+}
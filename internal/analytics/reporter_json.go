@@ -0,0 +1,25 @@
+package analytics
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONReporter renders a single indented JSON object per report, matching
+// the reportDoc schema.
+type JSONReporter struct{}
+
+func (JSONReporter) Report(w io.Writer, report *StorageGrowthReport, fee int64) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(toDoc(report, fee))
+}
+
+// NDJSONReporter renders one compact JSON object per report, newline
+// terminated, so repeated calls against the same writer produce a valid
+// newline-delimited JSON stream for batch runs.
+type NDJSONReporter struct{}
+
+func (NDJSONReporter) Report(w io.Writer, report *StorageGrowthReport, fee int64) error {
+	return json.NewEncoder(w).Encode(toDoc(report, fee))
+}
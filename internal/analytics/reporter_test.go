@@ -0,0 +1,53 @@
+package analytics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestToDocSortsPerKeyByKey(t *testing.T) {
+	report := BuildReport("C1", "hash1", 42, map[string]int64{
+		"zzz": 1, "aaa": 2, "mmm": 3,
+	}, map[string]int64{
+		"zzz": 1, "aaa": 2, "mmm": 3,
+	})
+
+	for i := 0; i < 5; i++ {
+		doc := toDoc(report, 10)
+		var keys []string
+		for _, kd := range doc.PerKey {
+			keys = append(keys, kd.Key)
+		}
+		want := []string{"aaa", "mmm", "zzz"}
+		if strings.Join(keys, ",") != strings.Join(want, ",") {
+			t.Fatalf("toDoc PerKey order = %v, want %v", keys, want)
+		}
+	}
+}
+
+func TestCSVReporterSortsPerKeyByKey(t *testing.T) {
+	report := BuildReport("C1", "hash1", 42, map[string]int64{
+		"zzz": 1, "aaa": 2, "mmm": 3,
+	}, map[string]int64{
+		"zzz": 1, "aaa": 2, "mmm": 3,
+	})
+
+	var buf bytes.Buffer
+	if err := (CSVReporter{}).Report(&buf, report, 10); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header + 3 rows, got %d lines: %q", len(lines), buf.String())
+	}
+	var keys []string
+	for _, line := range lines[1:] {
+		keys = append(keys, strings.Split(line, ",")[2])
+	}
+	want := []string{"aaa", "mmm", "zzz"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Fatalf("CSV row order = %v, want %v", keys, want)
+	}
+}
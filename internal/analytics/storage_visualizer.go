@@ -2,20 +2,35 @@ package analytics
 
 import (
 	"fmt"
+	"io"
+	"os"
 )
 
-func PrintStorageReport(report *StorageGrowthReport, fee int64) {
-	fmt.Println("📦 Contract Storage Growth Report")
-	fmt.Println("--------------------------------")
-	fmt.Printf("Before: %d bytes\n", report.BeforeBytes)
-	fmt.Printf("After:  %d bytes\n", report.AfterBytes)
-	fmt.Printf("Delta:  %+d bytes\n", report.DeltaBytes)
-	fmt.Printf("Fee Impact: %d stroops\n\n", fee)
+// TextReporter renders the same human-formatted block PrintStorageReport
+// has always printed to stdout.
+type TextReporter struct{}
+
+func (TextReporter) Report(w io.Writer, report *StorageGrowthReport, fee int64) error {
+	fmt.Fprintln(w, "📦 Contract Storage Growth Report")
+	fmt.Fprintln(w, "--------------------------------")
+	fmt.Fprintf(w, "Before: %d bytes\n", report.BeforeBytes)
+	fmt.Fprintf(w, "After:  %d bytes\n", report.AfterBytes)
+	fmt.Fprintf(w, "Delta:  %+d bytes\n", report.DeltaBytes)
+	fmt.Fprintf(w, "Fee Impact: %d stroops\n\n", fee)
 
-	fmt.Println("Per-Key Changes:")
+	fmt.Fprintln(w, "Per-Key Changes:")
 	for key, delta := range report.PerKeyDelta {
 		if delta != 0 {
-			fmt.Printf("  %s: %+d bytes\n", key, delta)
+			fmt.Fprintf(w, "  %s: %+d bytes\n", key, delta)
 		}
 	}
+
+	return nil
+}
+
+// PrintStorageReport writes a human-readable storage growth report to
+// stdout. Callers that need JSON, NDJSON or CSV output should use
+// ReporterFor instead.
+func PrintStorageReport(report *StorageGrowthReport, fee int64) {
+	_ = TextReporter{}.Report(os.Stdout, report, fee)
 }
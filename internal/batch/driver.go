@@ -0,0 +1,124 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/analytics"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// Driver runs every Envelope a Source yields through a pool of
+// simulator.Daemon workers and rolls the results up by contract ID.
+type Driver struct {
+	// Workers is the number of simulator.Daemon processes to run
+	// concurrently. Defaults to 1.
+	Workers int
+	// DaemonConfig is passed to simulator.NewDaemon for each worker.
+	DaemonConfig simulator.DaemonConfig
+}
+
+// Run drains src across d.Workers daemons and returns the aggregated
+// Rollup. It stops at the first error from src (other than io.EOF) or from
+// a worker.
+func (d *Driver) Run(ctx context.Context, src Source) (*Rollup, error) {
+	workers := d.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	// runCtx is canceled as soon as any worker reports an error, so the
+	// source goroutine (blocked sending on envelopes, with nobody left to
+	// drain it) unblocks instead of wedging Run forever.
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	envelopes := make(chan *Envelope)
+	srcErr := make(chan error, 1)
+	go func() {
+		defer close(envelopes)
+		for {
+			env, err := src.Next(runCtx)
+			if err == io.EOF {
+				srcErr <- nil
+				return
+			}
+			if err != nil {
+				srcErr <- err
+				return
+			}
+			select {
+			case envelopes <- env:
+			case <-runCtx.Done():
+				srcErr <- runCtx.Err()
+				return
+			}
+		}
+	}()
+
+	rollup := NewRollup()
+	var rollupMu sync.Mutex
+
+	var wg sync.WaitGroup
+	workerErrs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		daemon, err := simulator.NewDaemon(d.DaemonConfig)
+		if err != nil {
+			return nil, fmt.Errorf("batch: starting worker %d: %w", i, err)
+		}
+
+		wg.Add(1)
+		go func(daemon *simulator.Daemon) {
+			defer wg.Done()
+			defer daemon.Close()
+
+			for env := range envelopes {
+				resp, err := daemon.Simulate(runCtx, &simulator.SimulationRequest{
+					EnvelopeXdr:   env.EnvelopeXdr,
+					ResultMetaXdr: env.ResultMetaXdr,
+				})
+				if err != nil {
+					// Report the first failure, cancel so the source
+					// goroutine (and any sibling workers still draining
+					// envelopes) unwind promptly, and stop. Continuing to
+					// range over envelopes while workerErrs (buffered to
+					// workers) fills up would otherwise block this worker
+					// forever, stalling the source goroutine and
+					// deadlocking wg.Wait.
+					workerErrs <- err
+					cancel()
+					return
+				}
+				if resp.Status != "success" {
+					continue
+				}
+
+				report := analytics.BuildReport(env.ContractID, analytics.EnvelopeHash(env.EnvelopeXdr), env.LedgerSeq, env.BeforeBytes, env.AfterBytes)
+
+				rollupMu.Lock()
+				rollup.Add(env.ContractID, report, env.FeeStroops)
+				rollupMu.Unlock()
+			}
+		}(daemon)
+	}
+
+	wg.Wait()
+	close(workerErrs)
+
+	// Check worker errors before the source error: a worker failure
+	// cancels runCtx, which makes the source goroutine report a spurious
+	// context.Canceled that would otherwise mask the real cause.
+	for err := range workerErrs {
+		if err != nil {
+			return nil, fmt.Errorf("batch: simulation failed: %w", err)
+		}
+	}
+	if err := <-srcErr; err != nil && err != context.Canceled {
+		return nil, fmt.Errorf("batch: reading source: %w", err)
+	}
+
+	rollup.Finalize()
+	return rollup, nil
+}
@@ -0,0 +1,69 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newHorizonTestServer(t *testing.T, records []horizonTx) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var page horizonTxPage
+		if r.URL.Query().Get("cursor") == "" {
+			page.Embedded.Records = records
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func drainSource(t *testing.T, src *HorizonSource) []*Envelope {
+	t.Helper()
+	var envs []*Envelope
+	for {
+		env, err := src.Next(context.Background())
+		if err == io.EOF {
+			return envs
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		envs = append(envs, env)
+	}
+}
+
+func TestHorizonSourceEndLedgerZeroIsUnbounded(t *testing.T) {
+	srv := newHorizonTestServer(t, []horizonTx{
+		{Ledger: 100, EnvelopeXdr: "a", PagingToken: "1"},
+		{Ledger: 200, EnvelopeXdr: "b", PagingToken: "2"},
+		{Ledger: 300, EnvelopeXdr: "c", PagingToken: "3"},
+	})
+
+	src := NewHorizonSource(srv.URL, 0, 0, "")
+	envs := drainSource(t, src)
+
+	if len(envs) != 3 {
+		t.Fatalf("expected all 3 transactions with EndLedger=0 (unbounded), got %d", len(envs))
+	}
+}
+
+func TestHorizonSourceRespectsLedgerRange(t *testing.T) {
+	srv := newHorizonTestServer(t, []horizonTx{
+		{Ledger: 100, EnvelopeXdr: "a", PagingToken: "1"},
+		{Ledger: 200, EnvelopeXdr: "b", PagingToken: "2"},
+		{Ledger: 300, EnvelopeXdr: "c", PagingToken: "3"},
+	})
+
+	src := NewHorizonSource(srv.URL, 150, 250, "")
+	envs := drainSource(t, src)
+
+	if len(envs) != 1 || envs[0].LedgerSeq != 200 {
+		t.Fatalf("expected only ledger 200 within [150,250], got %+v", envs)
+	}
+}
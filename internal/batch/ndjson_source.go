@@ -0,0 +1,52 @@
+package batch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// NDJSONSource reads Envelopes from a newline-delimited JSON file, one
+// Envelope object per line.
+type NDJSONSource struct {
+	file    *os.File
+	scanner *bufio.Scanner
+}
+
+// OpenNDJSONSource opens path for streaming Envelopes.
+func OpenNDJSONSource(path string) (*NDJSONSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("batch: opening ndjson source: %w", err)
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	return &NDJSONSource{file: f, scanner: scanner}, nil
+}
+
+func (s *NDJSONSource) Next(ctx context.Context) (*Envelope, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if !s.scanner.Scan() {
+		if err := s.scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	var env Envelope
+	if err := json.Unmarshal(s.scanner.Bytes(), &env); err != nil {
+		return nil, fmt.Errorf("batch: decoding ndjson line: %w", err)
+	}
+	return &env, nil
+}
+
+// Close releases the underlying file handle.
+func (s *NDJSONSource) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,83 @@
+package batch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// WriteRollup renders rollup to w in one of "text", "json", "ndjson" or
+// "csv" - the same format names analytics.ReporterFor accepts, so a single
+// --output flag can select both single-transaction and rollup output.
+func WriteRollup(w io.Writer, rollup *Rollup, format string) error {
+	rows := sortedContracts(rollup)
+
+	switch format {
+	case "", "text":
+		return writeRollupText(w, rows)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case "ndjson":
+		enc := json.NewEncoder(w)
+		for _, c := range rows {
+			if err := enc.Encode(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		return writeRollupCSV(w, rows)
+	default:
+		return fmt.Errorf("batch: unknown output format %q", format)
+	}
+}
+
+// sortedContracts returns rollup.Contracts's values sorted by contract ID.
+// rollup.Contracts is a map, so its iteration order is nondeterministic;
+// XDRDirSource already sorts its directory listing for reproducible runs,
+// and rollup output should be just as diffable.
+func sortedContracts(rollup *Rollup) []*ContractRollup {
+	rows := make([]*ContractRollup, 0, len(rollup.Contracts))
+	for _, c := range rollup.Contracts {
+		rows = append(rows, c)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ContractID < rows[j].ContractID })
+	return rows
+}
+
+func writeRollupText(w io.Writer, rows []*ContractRollup) error {
+	for _, c := range rows {
+		fmt.Fprintf(w, "%s: %d txs, total delta %+d bytes (p50 %+d, p95 %+d), total fee %d stroops\n",
+			c.ContractID, c.TxCount, c.TotalDelta, c.P50Delta, c.P95Delta, c.TotalFee)
+	}
+	return nil
+}
+
+func writeRollupCSV(w io.Writer, rows []*ContractRollup) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	header := []string{"contract_id", "tx_count", "total_delta_bytes", "p50_delta_bytes", "p95_delta_bytes", "total_fee_stroops"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, c := range rows {
+		row := []string{
+			c.ContractID,
+			fmt.Sprintf("%d", c.TxCount),
+			fmt.Sprintf("%d", c.TotalDelta),
+			fmt.Sprintf("%d", c.P50Delta),
+			fmt.Sprintf("%d", c.P95Delta),
+			fmt.Sprintf("%d", c.TotalFee),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	return cw.Error()
+}
@@ -0,0 +1,59 @@
+package batch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/analytics"
+)
+
+func rollupForOrderTest() *Rollup {
+	r := NewRollup()
+	for _, id := range []string{"zzz", "aaa", "mmm"} {
+		r.Add(id, &analytics.StorageGrowthReport{DeltaBytes: 1}, 1)
+	}
+	r.Finalize()
+	return r
+}
+
+func TestWriteRollupSortsRowsByContractID(t *testing.T) {
+	want := []string{"aaa", "mmm", "zzz"}
+
+	for _, format := range []string{"text", "json", "ndjson", "csv"} {
+		var buf bytes.Buffer
+		if err := WriteRollup(&buf, rollupForOrderTest(), format); err != nil {
+			t.Fatalf("WriteRollup(%q): %v", format, err)
+		}
+
+		ids := extractContractIDOrder(t, format, buf.String())
+		if strings.Join(ids, ",") != strings.Join(want, ",") {
+			t.Fatalf("WriteRollup(%q) order = %v, want %v", format, ids, want)
+		}
+	}
+}
+
+func extractContractIDOrder(t *testing.T, format, out string) []string {
+	t.Helper()
+	var ids []string
+	switch format {
+	case "text":
+		for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+			ids = append(ids, strings.SplitN(line, ":", 2)[0])
+		}
+	case "json":
+		for _, field := range strings.Split(out, `"contract_id": "`)[1:] {
+			ids = append(ids, strings.SplitN(field, `"`, 2)[0])
+		}
+	case "ndjson":
+		for _, field := range strings.Split(out, `"contract_id":"`)[1:] {
+			ids = append(ids, strings.SplitN(field, `"`, 2)[0])
+		}
+	case "csv":
+		lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+		for _, line := range lines[1:] {
+			ids = append(ids, strings.SplitN(line, ",", 2)[0])
+		}
+	}
+	return ids
+}
@@ -0,0 +1,76 @@
+package batch
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/analytics"
+)
+
+// ContractRollup aggregates every simulated transaction's storage growth
+// report for a single contract.
+type ContractRollup struct {
+	ContractID string `json:"contract_id"`
+	TxCount    int    `json:"tx_count"`
+	TotalDelta int64  `json:"total_delta_bytes"`
+	TotalFee   int64  `json:"total_fee_stroops"`
+	P50Delta   int64  `json:"p50_delta_bytes"`
+	P95Delta   int64  `json:"p95_delta_bytes"`
+
+	deltas []int64
+}
+
+// Rollup aggregates ContractRollups across every contract a batch run
+// touched.
+type Rollup struct {
+	Contracts map[string]*ContractRollup
+}
+
+// NewRollup returns an empty Rollup.
+func NewRollup() *Rollup {
+	return &Rollup{Contracts: make(map[string]*ContractRollup)}
+}
+
+// Add folds one transaction's storage growth report and fee into the
+// rollup for contractID.
+func (r *Rollup) Add(contractID string, report *analytics.StorageGrowthReport, fee int64) {
+	c, ok := r.Contracts[contractID]
+	if !ok {
+		c = &ContractRollup{ContractID: contractID}
+		r.Contracts[contractID] = c
+	}
+
+	c.TxCount++
+	c.TotalDelta += report.DeltaBytes
+	c.TotalFee += fee
+	c.deltas = append(c.deltas, report.DeltaBytes)
+}
+
+// Finalize computes each contract's p50/p95 delta from its accumulated
+// samples. Call it once after every Add, before reading the rollup.
+func (r *Rollup) Finalize() {
+	for _, c := range r.Contracts {
+		c.P50Delta = percentile(c.deltas, 0.50)
+		c.P95Delta = percentile(c.deltas, 0.95)
+	}
+}
+
+// percentile returns the p-th percentile (0 <= p <= 1) of samples using the
+// nearest-rank method: rank = ceil(p * n), clamped to [1, n]. samples is
+// sorted in place.
+func percentile(samples []int64, p float64) int64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	rank := int(math.Ceil(p * float64(len(samples))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(samples) {
+		rank = len(samples)
+	}
+	return samples[rank-1]
+}
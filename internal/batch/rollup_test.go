@@ -0,0 +1,62 @@
+package batch
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/analytics"
+)
+
+func TestPercentileNearestRank(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []int64
+		p       float64
+		want    int64
+	}{
+		{"empty", nil, 0.5, 0},
+		{"single", []int64{7}, 0.5, 7},
+		{"single_p0", []int64{7}, 0, 7},
+		{"p50_even_count", []int64{10, 20, 30, 40}, 0.5, 20},
+		{"p95_ten_samples", []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 0.95, 10},
+		{"p0_clamped_to_first", []int64{5, 1, 3}, 0, 1},
+		{"p100_clamped_to_last", []int64{5, 1, 3}, 1, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := append([]int64(nil), tt.samples...)
+			got := percentile(samples, tt.p)
+			if got != tt.want {
+				t.Fatalf("percentile(%v, %v) = %d, want %d", tt.samples, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollupFinalizeComputesPerContractPercentiles(t *testing.T) {
+	r := NewRollup()
+	for _, delta := range []int64{10, 20, 30, 40} {
+		r.Add("C1", &analytics.StorageGrowthReport{DeltaBytes: delta}, 100)
+	}
+	r.Finalize()
+
+	c := r.Contracts["C1"]
+	if c == nil {
+		t.Fatalf("expected rollup for contract C1")
+	}
+	if c.TxCount != 4 {
+		t.Fatalf("TxCount = %d, want 4", c.TxCount)
+	}
+	if c.TotalDelta != 100 {
+		t.Fatalf("TotalDelta = %d, want 100", c.TotalDelta)
+	}
+	if c.TotalFee != 400 {
+		t.Fatalf("TotalFee = %d, want 400", c.TotalFee)
+	}
+	if c.P50Delta != 20 {
+		t.Fatalf("P50Delta = %d, want 20", c.P50Delta)
+	}
+	if c.P95Delta != 40 {
+		t.Fatalf("P95Delta = %d, want 40", c.P95Delta)
+	}
+}
@@ -0,0 +1,34 @@
+// Package batch fans a range of historical transactions out across a pool
+// of simulator.Daemon workers and rolls their StorageGrowthReports up by
+// contract ID.
+package batch
+
+import (
+	"context"
+	"io"
+)
+
+// Envelope pairs the XDR inputs a Daemon needs to simulate one historical
+// transaction with the context used to attribute its StorageGrowthReport:
+// which contract it touched, which ledger it landed in, what it cost, and
+// (when known ahead of time) the storage footprint it changed.
+type Envelope struct {
+	ContractID    string
+	LedgerSeq     uint32
+	EnvelopeXdr   string
+	ResultMetaXdr string
+	FeeStroops    int64
+	BeforeBytes   map[string]int64
+	AfterBytes    map[string]int64
+}
+
+// Source yields Envelopes to simulate, one at a time, until exhausted.
+// Implementations should return io.EOF once there is nothing left to read,
+// and should stop promptly if ctx is canceled mid-fetch.
+type Source interface {
+	Next(ctx context.Context) (*Envelope, error)
+}
+
+// ErrDone is an alias for io.EOF, the sentinel Source implementations
+// return once exhausted.
+var ErrDone = io.EOF
@@ -0,0 +1,75 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// envelopeSuffix and metaSuffix name the two files a XDRDirSource expects
+// per transaction: "<id>.envelope.xdr" and "<id>.meta.xdr".
+const (
+	envelopeSuffix = ".envelope.xdr"
+	metaSuffix     = ".meta.xdr"
+)
+
+// XDRDirSource reads Envelopes from a directory of pre-extracted XDR blobs,
+// one pair of files per transaction. It does not know about fees, contract
+// ID, or before/after storage state - it passes the meta XDR through
+// opaquely without decoding it; callers needing those should use
+// NDJSONSource or populate them from another source of truth after the
+// fact.
+type XDRDirSource struct {
+	dir   string
+	ids   []string
+	index int
+}
+
+// OpenXDRDirSource scans dir for "<id>.envelope.xdr" files and pairs each
+// with its "<id>.meta.xdr" counterpart, sorted by id for reproducible runs.
+func OpenXDRDirSource(dir string) (*XDRDirSource, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("batch: reading xdr dir: %w", err)
+	}
+
+	var ids []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), envelopeSuffix); ok {
+			ids = append(ids, name)
+		}
+	}
+	sort.Strings(ids)
+
+	return &XDRDirSource{dir: dir, ids: ids}, nil
+}
+
+func (s *XDRDirSource) Next(ctx context.Context) (*Envelope, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.index >= len(s.ids) {
+		return nil, io.EOF
+	}
+
+	id := s.ids[s.index]
+	s.index++
+
+	envelopeXdr, err := os.ReadFile(filepath.Join(s.dir, id+envelopeSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("batch: reading envelope xdr for %s: %w", id, err)
+	}
+	resultMetaXdr, err := os.ReadFile(filepath.Join(s.dir, id+metaSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("batch: reading result meta xdr for %s: %w", id, err)
+	}
+
+	return &Envelope{
+		EnvelopeXdr:   string(envelopeXdr),
+		ResultMetaXdr: string(resultMetaXdr),
+	}, nil
+}
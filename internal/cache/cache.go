@@ -0,0 +1,293 @@
+// Package cache implements a Bitcask-style, append-only keyed log for
+// memoizing simulator.SimulationResponse values. Every Put appends a record
+// to the active data file; an in-memory hash index maps each key to the
+// file and offset of its most recent value, so Get never needs to scan.
+package cache
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Options configures a Store.
+type Options struct {
+	// Dir holds the store's data and hint files. Required.
+	Dir string
+	// MaxFileSize rotates the active data file once appending a record
+	// would exceed it. Zero disables rotation.
+	MaxFileSize int64
+	// TTL expires entries older than this duration on Get. Zero disables
+	// expiry. This is in addition to callers folding a protocol version
+	// into the key itself, which is the usual way stale entries are
+	// invalidated on a version bump.
+	TTL time.Duration
+}
+
+// indexEntry locates a value within a data file.
+type indexEntry struct {
+	fileID    uint32
+	valuePos  int64
+	valueSize int64
+	tstamp    int64
+}
+
+// Store is a Bitcask-style keyed log: Put appends to the active data file
+// and updates an in-memory hash index; Get reads the index then does a
+// single seek into the file it names.
+type Store struct {
+	mu  sync.Mutex
+	opt Options
+
+	activeID   uint32
+	activeFile *os.File
+	activeSize int64
+
+	index map[string]indexEntry
+}
+
+// Open rebuilds the index - from each data file's hint file when present,
+// otherwise by scanning the data file directly - and readies the store for
+// Put/Get.
+func Open(opt Options) (*Store, error) {
+	if opt.Dir == "" {
+		return nil, fmt.Errorf("cache: Dir is required")
+	}
+	if err := os.MkdirAll(opt.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cache: creating dir: %w", err)
+	}
+
+	s := &Store{opt: opt, index: make(map[string]indexEntry)}
+
+	ids, err := dataFileIDs(opt.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("cache: listing data files: %w", err)
+	}
+	for _, id := range ids {
+		if err := s.loadFile(id); err != nil {
+			return nil, fmt.Errorf("cache: loading data file %d: %w", id, err)
+		}
+	}
+	if len(ids) > 0 {
+		s.activeID = ids[len(ids)-1]
+	}
+
+	f, err := os.OpenFile(dataFilePath(opt.Dir, s.activeID), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("cache: opening active file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	s.activeFile = f
+	s.activeSize = info.Size()
+
+	return s, nil
+}
+
+func (s *Store) loadFile(id uint32) error {
+	if entries, ok := readHintFile(hintFilePath(s.opt.Dir, id)); ok {
+		for key, e := range entries {
+			e.fileID = id
+			s.index[key] = e
+		}
+		return nil
+	}
+	return s.scanFile(id)
+}
+
+// scanFile rebuilds index entries for data file id by replaying its
+// records in order; a later record for the same key overwrites an earlier
+// one, and a zero-length value is treated as a tombstone.
+//
+// A non-EOF decode error - a torn header or a CRC mismatch - means the
+// file's tail was corrupted, most often by a process that died mid-write.
+// That is exactly what the per-record CRC exists to detect, so scanFile
+// stops there and keeps everything decoded before the bad tail rather than
+// failing Open outright and bricking the cache.
+func (s *Store) scanFile(id uint32) error {
+	f, err := os.Open(dataFilePath(s.opt.Dir, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	var pos int64
+	for {
+		start := pos
+		key, value, tstamp, err := decodeRecordAt(f)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			break
+		}
+		pos = start + headerSize + int64(len(key)) + int64(len(value))
+
+		if len(value) == 0 {
+			delete(s.index, string(key))
+			continue
+		}
+		s.index[string(key)] = indexEntry{
+			fileID:    id,
+			valuePos:  start + headerSize + int64(len(key)),
+			valueSize: int64(len(value)),
+			tstamp:    tstamp,
+		}
+	}
+	return nil
+}
+
+// Put appends value under key to the active data file, rotating to a fresh
+// file first if the write would exceed MaxFileSize.
+func (s *Store) Put(key, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tstamp := time.Now().Unix()
+	rec := encodeRecord(key, value, tstamp)
+
+	if s.opt.MaxFileSize > 0 && s.activeSize+int64(len(rec)) > s.opt.MaxFileSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	off := s.activeSize
+	if _, err := s.activeFile.Write(rec); err != nil {
+		return fmt.Errorf("cache: writing record: %w", err)
+	}
+
+	s.index[string(key)] = indexEntry{
+		fileID:    s.activeID,
+		valuePos:  off + headerSize + int64(len(key)),
+		valueSize: int64(len(value)),
+		tstamp:    tstamp,
+	}
+	s.activeSize = off + int64(len(rec))
+
+	return nil
+}
+
+// Get reports the value last Put under key, or ok=false if the key is
+// absent or its entry has expired under Options.TTL.
+//
+// The data file read happens outside s.mu, so a concurrent Merge can
+// delete the file an already-looked-up entry points to between the
+// unlock and the os.Open below. When that happens, re-check the index
+// under the lock: Merge removes stale files and swaps in the new index
+// while holding s.mu, so the retry either finds the entry's new location
+// or learns the key is gone.
+func (s *Store) Get(key []byte) ([]byte, bool, error) {
+	entry, ok := s.lookup(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	value, err := s.readEntry(entry)
+	if os.IsNotExist(err) {
+		entry, ok = s.lookup(key)
+		if !ok {
+			return nil, false, nil
+		}
+		value, err = s.readEntry(entry)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+func (s *Store) lookup(key []byte) (indexEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.index[string(key)]
+	if !ok {
+		return indexEntry{}, false
+	}
+	if s.opt.TTL > 0 && time.Since(time.Unix(entry.tstamp, 0)) > s.opt.TTL {
+		return indexEntry{}, false
+	}
+	return entry, true
+}
+
+func (s *Store) readEntry(entry indexEntry) ([]byte, error) {
+	f, err := os.Open(dataFilePath(s.opt.Dir, entry.fileID))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	value := make([]byte, entry.valueSize)
+	if _, err := f.ReadAt(value, entry.valuePos); err != nil {
+		return nil, fmt.Errorf("cache: reading value: %w", err)
+	}
+	return value, nil
+}
+
+// rotate flushes a hint file for the current active file, then opens a new
+// active file with the next sequential ID.
+func (s *Store) rotate() error {
+	if err := writeHintFile(hintFilePath(s.opt.Dir, s.activeID), s.index, s.activeID); err != nil {
+		return fmt.Errorf("cache: writing hint file: %w", err)
+	}
+	if err := s.activeFile.Close(); err != nil {
+		return err
+	}
+
+	s.activeID++
+	f, err := os.OpenFile(dataFilePath(s.opt.Dir, s.activeID), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	s.activeFile = f
+	s.activeSize = 0
+	return nil
+}
+
+// Close flushes a hint file for the active data file and closes its handle.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := writeHintFile(hintFilePath(s.opt.Dir, s.activeID), s.index, s.activeID); err != nil {
+		return err
+	}
+	return s.activeFile.Close()
+}
+
+func dataFilePath(dir string, id uint32) string {
+	return filepath.Join(dir, fmt.Sprintf("%06d.data", id))
+}
+
+func hintFilePath(dir string, id uint32) string {
+	return filepath.Join(dir, fmt.Sprintf("%06d.hint", id))
+}
+
+// dataFileIDs returns the IDs of every data file in dir, sorted ascending.
+func dataFileIDs(dir string) ([]uint32, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint32
+	for _, e := range entries {
+		var id uint32
+		if _, err := fmt.Sscanf(e.Name(), "%06d.data", &id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+)
+
+// hintHeaderSize is the fixed-width prefix of every hint entry: keysz,
+// valuePos, valueSize and tstamp.
+const hintHeaderSize = 4 + 8 + 8 + 8
+
+// writeHintFile persists the subset of index belonging to fileID as a
+// sequence of {keysz, valuePos, valueSize, tstamp, key} entries, so a later
+// Open can rebuild that slice of the index without rescanning the data file.
+func writeHintFile(path string, index map[string]indexEntry, fileID uint32) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for key, e := range index {
+		if e.fileID != fileID {
+			continue
+		}
+		if err := writeHintEntry(f, key, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeHintEntry(w io.Writer, key string, e indexEntry) error {
+	header := make([]byte, hintHeaderSize)
+	binary.BigEndian.PutUint32(header[0:4], uint32(len(key)))
+	binary.BigEndian.PutUint64(header[4:12], uint64(e.valuePos))
+	binary.BigEndian.PutUint64(header[12:20], uint64(e.valueSize))
+	binary.BigEndian.PutUint64(header[20:28], uint64(e.tstamp))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte(key))
+	return err
+}
+
+// readHintFile loads a hint file written by writeHintFile. ok is false if
+// the file does not exist, in which case the caller should fall back to
+// scanning the corresponding data file.
+func readHintFile(path string) (entries map[string]indexEntry, ok bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	entries = make(map[string]indexEntry)
+	for {
+		header := make([]byte, hintHeaderSize)
+		if _, err := io.ReadFull(f, header); err != nil {
+			break
+		}
+		keysz := binary.BigEndian.Uint32(header[0:4])
+		valuePos := int64(binary.BigEndian.Uint64(header[4:12]))
+		valueSize := int64(binary.BigEndian.Uint64(header[12:20]))
+		tstamp := int64(binary.BigEndian.Uint64(header[20:28]))
+
+		key := make([]byte, keysz)
+		if _, err := io.ReadFull(f, key); err != nil {
+			break
+		}
+
+		entries[string(key)] = indexEntry{valuePos: valuePos, valueSize: valueSize, tstamp: tstamp}
+	}
+	return entries, true
+}
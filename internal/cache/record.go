@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// headerSize is the fixed-width prefix of every record: crc32, tstamp,
+// keysz and valsz.
+const headerSize = 4 + 8 + 4 + 4
+
+// errCorruptRecord is returned when a record's checksum does not match its
+// payload, which can happen if a data file was truncated mid-write.
+var errCorruptRecord = errors.New("cache: corrupt record")
+
+// encodeRecord serializes key/value into the on-disk record layout:
+// crc32(4) | tstamp(8) | keysz(4) | valsz(4) | key | value.
+func encodeRecord(key, value []byte, tstamp int64) []byte {
+	buf := make([]byte, headerSize+len(key)+len(value))
+	binary.BigEndian.PutUint64(buf[4:12], uint64(tstamp))
+	binary.BigEndian.PutUint32(buf[12:16], uint32(len(key)))
+	binary.BigEndian.PutUint32(buf[16:20], uint32(len(value)))
+	copy(buf[headerSize:], key)
+	copy(buf[headerSize+len(key):], value)
+	binary.BigEndian.PutUint32(buf[0:4], crc32.ChecksumIEEE(buf[4:]))
+	return buf
+}
+
+// decodeRecordAt reads and validates a single record from r, returning the
+// key, value and write timestamp it carries.
+func decodeRecordAt(r io.Reader) (key, value []byte, tstamp int64, err error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, nil, 0, err
+	}
+
+	wantCRC := binary.BigEndian.Uint32(header[0:4])
+	tstamp = int64(binary.BigEndian.Uint64(header[4:12]))
+	keysz := binary.BigEndian.Uint32(header[12:16])
+	valsz := binary.BigEndian.Uint32(header[16:20])
+
+	body := make([]byte, keysz+valsz)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, nil, 0, err
+	}
+
+	gotCRC := crc32.ChecksumIEEE(append(header[4:], body...))
+	if gotCRC != wantCRC {
+		return nil, nil, 0, errCorruptRecord
+	}
+
+	return body[:keysz], body[keysz:], tstamp, nil
+}
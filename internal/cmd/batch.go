@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/batch"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	batchNDJSON      string
+	batchXDRDir      string
+	batchHorizon     string
+	batchStartLedger uint32
+	batchEndLedger   uint32
+	batchCursor      string
+	batchWorkers     int
+	batchOutput      string
+	batchBinary      string
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Simulate a range of historical transactions and roll storage growth up by contract",
+	RunE:  runBatch,
+}
+
+func init() {
+	batchCmd.Flags().StringVar(&batchNDJSON, "ndjson", "", "NDJSON file of batch.Envelope records to simulate")
+	batchCmd.Flags().StringVar(&batchXDRDir, "xdr-dir", "", "directory of pre-extracted <id>.envelope.xdr/<id>.meta.xdr pairs (transactions from this source aren't attributed to a contract ID)")
+	batchCmd.Flags().StringVar(&batchHorizon, "horizon", "", "Horizon base URL to stream transactions from, e.g. https://horizon.stellar.org (transactions from this source aren't attributed to a contract ID)")
+	batchCmd.Flags().Uint32Var(&batchStartLedger, "start-ledger", 0, "first ledger (inclusive) to include when using --horizon")
+	batchCmd.Flags().Uint32Var(&batchEndLedger, "end-ledger", 0, "last ledger (inclusive) to include when using --horizon, 0 for unbounded")
+	batchCmd.Flags().StringVar(&batchCursor, "cursor", "", "Horizon paging cursor to resume from when using --horizon")
+	batchCmd.Flags().IntVar(&batchWorkers, "workers", 4, "number of simulator.Daemon workers to fan out across")
+	batchCmd.Flags().StringVar(&batchOutput, "output", "text", "rollup format: text, json, ndjson or csv")
+	batchCmd.Flags().StringVar(&batchBinary, "binary", "soroban-sim", "path to the Rust simulation binary")
+	rootCmd.AddCommand(batchCmd)
+}
+
+func runBatch(cmd *cobra.Command, args []string) error {
+	src, closeSrc, err := openBatchSource()
+	if err != nil {
+		return err
+	}
+	if closeSrc != nil {
+		defer closeSrc()
+	}
+
+	driver := &batch.Driver{
+		Workers:      batchWorkers,
+		DaemonConfig: simulator.DaemonConfig{BinaryPath: batchBinary},
+	}
+
+	rollup, err := driver.Run(cmd.Context(), src)
+	if err != nil {
+		return fmt.Errorf("running batch: %w", err)
+	}
+
+	return batch.WriteRollup(cmd.OutOrStdout(), rollup, batchOutput)
+}
+
+func openBatchSource() (batch.Source, func() error, error) {
+	switch {
+	case batchNDJSON != "":
+		src, err := batch.OpenNDJSONSource(batchNDJSON)
+		if err != nil {
+			return nil, nil, err
+		}
+		return src, src.Close, nil
+	case batchXDRDir != "":
+		src, err := batch.OpenXDRDirSource(batchXDRDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		return src, nil, nil
+	case batchHorizon != "":
+		return batch.NewHorizonSource(batchHorizon, batchStartLedger, batchEndLedger, batchCursor), nil, nil
+	default:
+		return nil, nil, fmt.Errorf("batch: one of --ndjson, --xdr-dir or --horizon is required")
+	}
+}
@@ -0,0 +1,21 @@
+// Package cmd wires hintents' subcommands together as the CLI entry point.
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "hintents",
+	Short: "Simulate Soroban transactions and report contract storage growth",
+}
+
+// Execute runs the CLI, returning the first error from the selected
+// subcommand.
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+func init() {
+	rootCmd.AddCommand(simulateCmd)
+}
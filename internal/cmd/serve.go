@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dotandev/hintents/internal/analytics"
+	"github.com/dotandev/hintents/internal/analytics/metrics"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+)
+
+var serveAddr string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP server exposing /simulate and Prometheus /metrics",
+	RunE:  runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", ":8080", "address to listen on")
+	serveCmd.Flags().StringVar(&simBinaryPath, "binary", "soroban-sim", "path to the Rust simulation binary")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// serveRequest is the JSON body accepted by POST /simulate. BeforeBytes and
+// AfterBytes are the per-key storage footprints the caller already
+// extracted from the ledger, used to build the StorageGrowthReport
+// alongside the simulator's own response.
+type serveRequest struct {
+	EnvelopeXdr   string           `json:"envelope_xdr"`
+	ResultMetaXdr string           `json:"result_meta_xdr"`
+	ContractID    string           `json:"contract_id"`
+	LedgerSeq     uint32           `json:"ledger_seq"`
+	FeeStroops    int64            `json:"fee_stroops"`
+	BeforeBytes   map[string]int64 `json:"before_bytes"`
+	AfterBytes    map[string]int64 `json:"after_bytes"`
+}
+
+type serveResponse struct {
+	Simulation *simulator.SimulationResponse  `json:"simulation"`
+	Report     *analytics.StorageGrowthReport `json:"report"`
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+	sim := simulator.New(simBinaryPath)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/simulate", serveSimulateHandler(sim, m))
+
+	fmt.Fprintf(cmd.OutOrStdout(), "listening on %s\n", serveAddr)
+	return http.ListenAndServe(serveAddr, mux)
+}
+
+func serveSimulateHandler(sim *simulator.Simulator, m *metrics.Metrics) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req serveRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("decoding request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		start := time.Now()
+		resp, err := sim.Simulate(&simulator.SimulationRequest{
+			EnvelopeXdr:   req.EnvelopeXdr,
+			ResultMetaXdr: req.ResultMetaXdr,
+		})
+		duration := time.Since(start)
+
+		status := "success"
+		if err != nil {
+			status = "error"
+		} else {
+			status = resp.Status
+		}
+
+		report := analytics.BuildReport(req.ContractID, analytics.EnvelopeHash(req.EnvelopeXdr), req.LedgerSeq, req.BeforeBytes, req.AfterBytes)
+		m.ObserveReport(report, req.FeeStroops, duration, status)
+
+		if err != nil {
+			http.Error(w, fmt.Sprintf("simulating transaction: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(serveResponse{Simulation: resp, Report: report})
+	}
+}
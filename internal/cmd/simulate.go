@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dotandev/hintents/internal/analytics"
+	"github.com/dotandev/hintents/internal/cache"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	simBinaryPath   string
+	simCacheDir     string
+	simCacheMaxSize int64
+	simNoCache      bool
+
+	simOutput      string
+	simContractID  string
+	simLedgerSeq   uint32
+	simFeeStroops  int64
+	simBeforeState string
+	simAfterState  string
+)
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate <envelope-xdr-file> <result-meta-xdr-file>",
+	Short: "Simulate a historical transaction and print the simulator's response, or a storage growth report when --before-state or --after-state is given",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runSimulate,
+}
+
+func init() {
+	simulateCmd.Flags().StringVar(&simBinaryPath, "binary", "soroban-sim", "path to the Rust simulation binary")
+	simulateCmd.Flags().StringVar(&simCacheDir, "cache-dir", defaultCacheDir(), "directory for the on-disk simulation result cache")
+	simulateCmd.Flags().Int64Var(&simCacheMaxSize, "cache-max-size", 64<<20, "max size in bytes of a single cache data file before rotation")
+	simulateCmd.Flags().BoolVar(&simNoCache, "no-cache", false, "disable the simulation result cache")
+
+	simulateCmd.Flags().StringVar(&simOutput, "output", "text", "storage growth report format: text, json, ndjson or csv")
+	simulateCmd.Flags().StringVar(&simContractID, "contract-id", "", "contract ID to attribute the storage growth report to")
+	simulateCmd.Flags().Uint32Var(&simLedgerSeq, "ledger-seq", 0, "ledger sequence the transaction was simulated against")
+	simulateCmd.Flags().Int64Var(&simFeeStroops, "fee-stroops", 0, "fee charged by the simulated transaction, in stroops")
+	simulateCmd.Flags().StringVar(&simBeforeState, "before-state", "", "JSON file of {key: bytes} storage sizes before the transaction")
+	simulateCmd.Flags().StringVar(&simAfterState, "after-state", "", "JSON file of {key: bytes} storage sizes after the transaction")
+}
+
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ".hintents-cache"
+	}
+	return filepath.Join(dir, "hintents", "simulate")
+}
+
+func runSimulate(cmd *cobra.Command, args []string) error {
+	envelopeXdr, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading envelope xdr: %w", err)
+	}
+	resultMetaXdr, err := os.ReadFile(args[1])
+	if err != nil {
+		return fmt.Errorf("reading result meta xdr: %w", err)
+	}
+
+	sim := simulator.New(simBinaryPath)
+	if !simNoCache {
+		store, err := cache.Open(cache.Options{Dir: simCacheDir, MaxFileSize: simCacheMaxSize})
+		if err != nil {
+			return fmt.Errorf("opening simulation cache: %w", err)
+		}
+		defer store.Close()
+		sim = sim.WithCache(store)
+	}
+
+	req := &simulator.SimulationRequest{
+		EnvelopeXdr:   string(envelopeXdr),
+		ResultMetaXdr: string(resultMetaXdr),
+	}
+
+	resp, err := sim.Simulate(req)
+	if err != nil {
+		return fmt.Errorf("simulating transaction: %w", err)
+	}
+
+	if simBeforeState == "" && simAfterState == "" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(resp)
+	}
+
+	before, err := loadStorageState(simBeforeState)
+	if err != nil {
+		return fmt.Errorf("loading --before-state: %w", err)
+	}
+	after, err := loadStorageState(simAfterState)
+	if err != nil {
+		return fmt.Errorf("loading --after-state: %w", err)
+	}
+
+	report := analytics.BuildReport(simContractID, analytics.EnvelopeHash(string(envelopeXdr)), simLedgerSeq, before, after)
+
+	reporter, err := analytics.ReporterFor(simOutput)
+	if err != nil {
+		return err
+	}
+	return reporter.Report(cmd.OutOrStdout(), report, simFeeStroops)
+}
+
+// loadStorageState reads a JSON file of {key: bytes} storage sizes. An
+// empty path yields an empty map rather than an error, so either
+// --before-state or --after-state can be omitted for a contract with no
+// prior footprint.
+func loadStorageState(path string) (map[string]int64, error) {
+	state := make(map[string]int64)
+	if path == "" {
+		return state, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
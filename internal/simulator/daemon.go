@@ -0,0 +1,429 @@
+package simulator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// daemonProtocolVersion is declared in the hello frame; the Rust side may
+// negotiate a lower version it also understands.
+const daemonProtocolVersion = 1
+
+// helloRequest is the first frame sent on every new connection, declaring
+// the protocol version this client speaks and the parallelism it would
+// like the worker pool to run at.
+type helloRequest struct {
+	Type        string `json:"type"`
+	Version     int    `json:"version"`
+	Parallelism int    `json:"parallelism"`
+}
+
+// helloResponse is the Rust side's reply to a hello frame, reporting the
+// version and parallelism it actually negotiated.
+type helloResponse struct {
+	Type        string `json:"type"`
+	Version     int    `json:"version"`
+	Parallelism int    `json:"parallelism"`
+}
+
+// DaemonConfig configures a Daemon.
+type DaemonConfig struct {
+	// BinaryPath is the long-running Rust simulation daemon to launch.
+	BinaryPath string
+	// Parallelism is the client's desired number of concurrently
+	// in-flight requests; the daemon clamps to whatever the Rust side
+	// negotiates back in its hello frame.
+	Parallelism int
+	// RestartBackoff is the base delay before relaunching a crashed
+	// process; it doubles on each consecutive crash (a process that exits
+	// before staying up for RestartBackoff, the first one included) up to
+	// MaxRestartBackoff, and resets once a relaunched process stays up
+	// for at least RestartBackoff before crashing again.
+	RestartBackoff time.Duration
+	// MaxRestartBackoff caps RestartBackoff's growth.
+	MaxRestartBackoff time.Duration
+	// HelloTimeout bounds how long spawn waits for the hello handshake
+	// to complete before giving up on a process that started but never
+	// speaks the protocol.
+	HelloTimeout time.Duration
+}
+
+// Daemon keeps a single Rust simulation binary alive across many Simulate
+// calls. Requests are multiplexed over the subprocess's stdin/stdout pipes
+// using a length-prefixed framing protocol and matched to callers by
+// SimulationRequest.RequestID. A crashed process is relaunched with
+// exponential backoff.
+type Daemon struct {
+	cfg DaemonConfig
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu        sync.Mutex
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	sem       chan struct{}
+	pending   map[string]chan frameResult
+	closed    bool
+	spawnedAt time.Time
+
+	// writeMu serializes writeFrame calls onto stdin; sem only bounds how
+	// many requests are in flight, so without this a second writer could
+	// interleave its length prefix or payload with another's mid-write.
+	writeMu sync.Mutex
+
+	nextID uint64
+}
+
+type frameResult struct {
+	resp *SimulationResponse
+	err  error
+}
+
+// NewDaemon launches cfg.BinaryPath, negotiates parallelism over a hello
+// frame, and starts the supervisor goroutine that restarts the process if
+// it exits unexpectedly. Call Close when done.
+func NewDaemon(cfg DaemonConfig) (*Daemon, error) {
+	if cfg.Parallelism <= 0 {
+		cfg.Parallelism = 8
+	}
+	if cfg.RestartBackoff <= 0 {
+		cfg.RestartBackoff = 200 * time.Millisecond
+	}
+	if cfg.MaxRestartBackoff <= 0 {
+		cfg.MaxRestartBackoff = 10 * time.Second
+	}
+	if cfg.HelloTimeout <= 0 {
+		cfg.HelloTimeout = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Daemon{
+		cfg:     cfg,
+		ctx:     ctx,
+		cancel:  cancel,
+		pending: make(map[string]chan frameResult),
+	}
+
+	if err := d.spawn(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	d.wg.Add(1)
+	go d.supervise()
+
+	return d, nil
+}
+
+// spawn starts the subprocess, performs the hello handshake, and starts
+// the frame reader for this process generation.
+func (d *Daemon) spawn() error {
+	cmd := exec.CommandContext(d.ctx, d.cfg.BinaryPath)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("simulator: stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("simulator: stdout pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("simulator: starting daemon: %w", err)
+	}
+
+	reader := bufio.NewReader(stdout)
+	parallelism, err := d.handshake(stdin, reader)
+	if err != nil {
+		// The process started but never completed the handshake; kill it
+		// rather than leaking it until some unrelated future event reaps
+		// it.
+		cmd.Process.Kill()
+		cmd.Wait()
+		return err
+	}
+
+	d.mu.Lock()
+	d.cmd = cmd
+	d.stdin = stdin
+	d.sem = make(chan struct{}, parallelism)
+	d.spawnedAt = time.Now()
+	d.mu.Unlock()
+
+	d.wg.Add(1)
+	go d.readLoop(cmd, reader)
+
+	return nil
+}
+
+// handshake sends the hello frame and waits for the daemon's reply,
+// bounded by cfg.HelloTimeout so a process that starts but never speaks
+// the protocol can't wedge the supervisor indefinitely. It returns the
+// parallelism to run at, clamped to a minimum of 1.
+func (d *Daemon) handshake(stdin io.Writer, reader *bufio.Reader) (int, error) {
+	hello, err := json.Marshal(helloRequest{
+		Type:        "hello",
+		Version:     daemonProtocolVersion,
+		Parallelism: d.cfg.Parallelism,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	d.writeMu.Lock()
+	err = writeFrame(stdin, hello)
+	d.writeMu.Unlock()
+	if err != nil {
+		return 0, fmt.Errorf("simulator: sending hello: %w", err)
+	}
+
+	type helloFrame struct {
+		raw []byte
+		err error
+	}
+	done := make(chan helloFrame, 1)
+	go func() {
+		raw, err := readFrame(reader)
+		done <- helloFrame{raw, err}
+	}()
+
+	var raw []byte
+	select {
+	case f := <-done:
+		if f.err != nil {
+			return 0, fmt.Errorf("simulator: reading hello response: %w", f.err)
+		}
+		raw = f.raw
+	case <-time.After(d.cfg.HelloTimeout):
+		return 0, fmt.Errorf("simulator: timed out waiting for hello response")
+	case <-d.ctx.Done():
+		return 0, d.ctx.Err()
+	}
+
+	var helloResp helloResponse
+	if err := json.Unmarshal(raw, &helloResp); err != nil {
+		return 0, fmt.Errorf("simulator: decoding hello response: %w", err)
+	}
+	if helloResp.Type != "hello" || helloResp.Version <= 0 {
+		return 0, fmt.Errorf("simulator: invalid hello response: %+v", helloResp)
+	}
+
+	parallelism := helloResp.Parallelism
+	if parallelism < 1 {
+		// A malfunctioning or outdated Rust side may reply with
+		// parallelism 0 (or omit the field); an unbuffered sem would
+		// then block every Simulate call forever.
+		parallelism = 1
+	}
+	return parallelism, nil
+}
+
+// readLoop dispatches frames from one process generation to the channel
+// waiting on each response's RequestID, until the pipe closes.
+func (d *Daemon) readLoop(cmd *exec.Cmd, reader *bufio.Reader) {
+	defer d.wg.Done()
+
+	for {
+		raw, err := readFrame(reader)
+		if err != nil {
+			d.failPending(fmt.Errorf("simulator: daemon connection lost: %w", err))
+			return
+		}
+
+		var resp SimulationResponse
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			continue
+		}
+
+		d.mu.Lock()
+		ch, ok := d.pending[resp.RequestID]
+		if ok {
+			delete(d.pending, resp.RequestID)
+		}
+		d.mu.Unlock()
+
+		if ok {
+			respCopy := resp
+			ch <- frameResult{resp: &respCopy}
+		}
+	}
+}
+
+// failPending delivers err to every request still waiting on the current
+// process generation, e.g. after a crash.
+func (d *Daemon) failPending(err error) {
+	d.mu.Lock()
+	pending := d.pending
+	d.pending = make(map[string]chan frameResult)
+	d.mu.Unlock()
+
+	for _, ch := range pending {
+		ch <- frameResult{err: err}
+	}
+}
+
+// supervise relaunches the subprocess with exponential backoff whenever it
+// exits while the Daemon has not been Closed. A process that crashes
+// before staying up for RestartBackoff is treated as a consecutive crash
+// and doubles the delay; only a process that stays up at least that long
+// resets it back to RestartBackoff.
+func (d *Daemon) supervise() {
+	defer d.wg.Done()
+
+	backoff := d.cfg.RestartBackoff
+	for {
+		d.mu.Lock()
+		cmd := d.cmd
+		spawnedAt := d.spawnedAt
+		d.mu.Unlock()
+
+		_ = cmd.Wait()
+
+		d.mu.Lock()
+		closed := d.closed
+		d.mu.Unlock()
+		if closed {
+			return
+		}
+
+		d.failPending(fmt.Errorf("simulator: daemon process exited, restarting"))
+
+		if time.Since(spawnedAt) >= d.cfg.RestartBackoff {
+			backoff = d.cfg.RestartBackoff
+		} else {
+			backoff *= 2
+			if backoff > d.cfg.MaxRestartBackoff {
+				backoff = d.cfg.MaxRestartBackoff
+			}
+		}
+
+		// Retry a failed spawn here rather than going back to Wait on the
+		// old, already-reaped cmd, which would return at once and reset
+		// the backoff.
+		for {
+			select {
+			case <-time.After(backoff):
+			case <-d.ctx.Done():
+				return
+			}
+
+			if err := d.spawn(); err == nil {
+				break
+			}
+			backoff *= 2
+			if backoff > d.cfg.MaxRestartBackoff {
+				backoff = d.cfg.MaxRestartBackoff
+			}
+		}
+	}
+}
+
+// Simulate sends req to the daemon and blocks until its matching response
+// arrives, the process crashes, ctx is canceled, or the Daemon is closed.
+// Concurrent callers are multiplexed over the same subprocess, bounded by
+// the parallelism negotiated in the hello frame.
+func (d *Daemon) Simulate(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("simulator: daemon is closed")
+	}
+	sem := d.sem
+	d.mu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+		defer func() { <-sem }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	ch := make(chan frameResult, 1)
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("simulator: daemon is closed")
+	}
+	d.nextID++
+	reqID := fmt.Sprintf("%d", d.nextID)
+	d.pending[reqID] = ch
+	stdin := d.stdin
+	d.mu.Unlock()
+
+	reqCopy := *req
+	reqCopy.RequestID = reqID
+	payload, err := json.Marshal(&reqCopy)
+	if err != nil {
+		d.mu.Lock()
+		delete(d.pending, reqID)
+		d.mu.Unlock()
+		return nil, fmt.Errorf("simulator: marshaling request: %w", err)
+	}
+
+	d.writeMu.Lock()
+	err = writeFrame(stdin, payload)
+	d.writeMu.Unlock()
+	if err != nil {
+		d.mu.Lock()
+		delete(d.pending, reqID)
+		d.mu.Unlock()
+		return nil, fmt.Errorf("simulator: writing request: %w", err)
+	}
+
+	select {
+	case result := <-ch:
+		return result.resp, result.err
+	case <-ctx.Done():
+		d.mu.Lock()
+		delete(d.pending, reqID)
+		d.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops the supervisor, asks the subprocess to exit by closing its
+// stdin, and waits for it to terminate before returning.
+func (d *Daemon) Close() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	stdin := d.stdin
+	cmd := d.cmd
+	d.mu.Unlock()
+
+	d.failPending(fmt.Errorf("simulator: daemon is closed"))
+
+	if stdin != nil {
+		stdin.Close()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		if cmd != nil && cmd.Process != nil {
+			cmd.Process.Kill()
+		}
+		<-done
+	}
+
+	d.cancel()
+	return nil
+}
@@ -0,0 +1,42 @@
+package simulator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// maxFrameSize guards against a corrupt or malicious length prefix causing
+// an unbounded allocation.
+const maxFrameSize = 64 << 20
+
+// writeFrame writes payload as a single frame: a 4-byte big-endian length
+// prefix followed by payload itself.
+func writeFrame(w io.Writer, payload []byte) error {
+	var lenBuf [4]byte
+	binary.BigEndian.PutUint32(lenBuf[:], uint32(len(payload)))
+	if _, err := w.Write(lenBuf[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// readFrame reads one length-prefixed frame from r.
+func readFrame(r io.Reader) ([]byte, error) {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(lenBuf[:])
+	if size > maxFrameSize {
+		return nil, fmt.Errorf("simulator: frame of %d bytes exceeds max %d", size, maxFrameSize)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
@@ -0,0 +1,68 @@
+package simulator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteReadFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	payload := []byte(`{"hello":"world"}`)
+
+	if err := writeFrame(&buf, payload); err != nil {
+		t.Fatalf("writeFrame: %v", err)
+	}
+
+	got, err := readFrame(&buf)
+	if err != nil {
+		t.Fatalf("readFrame: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("readFrame: got %q, want %q", got, payload)
+	}
+}
+
+func TestWriteReadFrameMultipleFrames(t *testing.T) {
+	var buf bytes.Buffer
+	frames := [][]byte{[]byte("one"), []byte("two"), []byte("three")}
+
+	for _, f := range frames {
+		if err := writeFrame(&buf, f); err != nil {
+			t.Fatalf("writeFrame: %v", err)
+		}
+	}
+
+	for _, want := range frames {
+		got, err := readFrame(&buf)
+		if err != nil {
+			t.Fatalf("readFrame: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("readFrame: got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestReadFrameRejectsOversizedLength(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write([]byte{0xFF, 0xFF, 0xFF, 0xFF})
+
+	if _, err := readFrame(&buf); err == nil {
+		t.Fatalf("readFrame: expected error for frame exceeding maxFrameSize")
+	}
+}
+
+func TestReadFrameEmptyPayload(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeFrame(&buf, nil); err != nil {
+		t.Fatalf("writeFrame: %v", err)
+	}
+
+	got, err := readFrame(&buf)
+	if err != nil {
+		t.Fatalf("readFrame: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("readFrame: got %q, want empty", got)
+	}
+}
@@ -8,12 +8,19 @@ type SimulationRequest struct {
 	ResultMetaXdr string `json:"result_meta_xdr"`
 	// XDR encoded LedgerHeader (optional, for context)
 	// LedgerHeaderXdr string `json:"ledger_header_xdr,omitempty"`
+
+	// RequestID correlates a request with its response on the Daemon's
+	// framed protocol. It is ignored by the one-shot, single-request
+	// Simulator.
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // SimulationResponse is the JSON object returned by the Rust binary via Stdout
 type SimulationResponse struct {
-	Status string   `json:"status"` // "success" or "error"
-	Error  string   `json:"error,omitempty"`
-	Events []string `json:"events,omitempty"` // Diagnostic events
-	Logs   []string `json:"logs,omitempty"`   // Host debug logs
+	// RequestID echoes the SimulationRequest.RequestID it answers.
+	RequestID string   `json:"request_id,omitempty"`
+	Status    string   `json:"status"` // "success" or "error"
+	Error     string   `json:"error,omitempty"`
+	Events    []string `json:"events,omitempty"` // Diagnostic events
+	Logs      []string `json:"logs,omitempty"`   // Host debug logs
 }
@@ -0,0 +1,100 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/dotandev/hintents/internal/cache"
+)
+
+// protocolVersion is bumped whenever a change to the simulation binary's
+// semantics would make previously cached responses unsafe to reuse. It is
+// folded into the cache key so a bump naturally misses instead of serving
+// stale results.
+const protocolVersion = 1
+
+// Simulator runs SimulationRequests against the Rust simulation binary,
+// one subprocess per call. Attach a cache.Store with WithCache to memoize
+// responses for repeated simulations of the same historical transaction.
+type Simulator struct {
+	binaryPath string
+	cache      *cache.Store
+}
+
+// New returns a Simulator that spawns binaryPath for each Simulate call.
+func New(binaryPath string) *Simulator {
+	return &Simulator{binaryPath: binaryPath}
+}
+
+// WithCache returns a copy of s that serves and populates store.
+func (s *Simulator) WithCache(store *cache.Store) *Simulator {
+	cp := *s
+	cp.cache = store
+	return &cp
+}
+
+// Simulate runs req through the Rust binary and returns its response,
+// serving from cache when a prior simulation of the same request is cached.
+func (s *Simulator) Simulate(req *SimulationRequest) (*SimulationResponse, error) {
+	var key []byte
+	if s.cache != nil {
+		key = cacheKey(req)
+		if raw, ok, err := s.cache.Get(key); err == nil && ok {
+			var resp SimulationResponse
+			if err := json.Unmarshal(raw, &resp); err == nil {
+				return &resp, nil
+			}
+		}
+	}
+
+	resp, err := s.run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		if raw, err := json.Marshal(resp); err == nil {
+			_ = s.cache.Put(key, raw)
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *Simulator) run(req *SimulationRequest) (*SimulationResponse, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("simulator: marshaling request: %w", err)
+	}
+
+	cmd := exec.Command(s.binaryPath)
+	cmd.Stdin = bytes.NewReader(payload)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("simulator: running %s: %w: %s", s.binaryPath, err, stderr.String())
+	}
+
+	var resp SimulationResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("simulator: decoding response: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// cacheKey derives a stable key from the request's XDR payloads and the
+// current protocol version.
+func cacheKey(req *SimulationRequest) []byte {
+	h := sha256.New()
+	h.Write([]byte{protocolVersion})
+	h.Write([]byte(req.EnvelopeXdr))
+	h.Write([]byte(req.ResultMetaXdr))
+	return h.Sum(nil)
+}